@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultBrowseTemplate is used when no -browse-template is given. It is
+// intentionally plain so operators can drop in their own template without
+// fighting embedded styling.
+const defaultBrowseTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>
+{{range .Breadcrumbs}}<a href="{{.Path}}">{{.Name}}</a> / {{end}}
+</p>
+<table>
+<thead><tr>
+<th><a href="?sort=name&order={{.NextOrder "name"}}">Name</a></th>
+<th><a href="?sort=size&order={{.NextOrder "size"}}">Size</a></th>
+<th><a href="?sort=time&order={{.NextOrder "time"}}">Modified</a></th>
+</tr></thead>
+<tbody>
+{{if .CanGoUp}}<tr><td><a href="{{.Up}}">..</a></td><td></td><td></td></tr>{{end}}
+{{range .Entries}}<tr>
+<td><a href="{{.Path}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td>
+<td>{{.Size}}</td>
+<td>{{.ModTime.Format "2006-01-02 15:04:05"}}</td>
+</tr>{{end}}
+</tbody>
+</table>
+</body>
+</html>
+`
+
+// browseEntry is one row of a directory listing rendered by Browse.
+type browseEntry struct {
+	Name      string
+	Path      string
+	IsDir     bool
+	Size      string
+	sizeBytes int64
+	ModTime   time.Time
+}
+
+// breadcrumb is one clickable segment of the current directory's path.
+type breadcrumb struct {
+	Name string
+	Path string
+}
+
+// listing is the view-model handed to the browse template.
+type listing struct {
+	Title       string
+	Breadcrumbs []breadcrumb
+	Entries     []browseEntry
+	CanGoUp     bool
+	Up          string
+	sort        string
+	order       string
+}
+
+// NextOrder returns the order a click on the given sort column should
+// request: "asc" unless that column is already sorted ascending.
+func (l listing) NextOrder(column string) string {
+	if l.sort == column && l.order == "asc" {
+		return "desc"
+	}
+	return "asc"
+}
+
+// Browse serves an HTML directory listing, modeled on Caddy's file_server
+// browse handler: a parsed template plus a ServeHTTP that builds a listing
+// view-model and renders it.
+type Browse struct {
+	tmpl *template.Template
+}
+
+// NewBrowse builds a Browse using the template at templatePath, or the
+// embedded default template when templatePath is empty.
+func NewBrowse(templatePath string) (*Browse, error) {
+	if templatePath == "" {
+		return &Browse{tmpl: template.Must(template.New("browse").Parse(defaultBrowseTemplate))}, nil
+	}
+
+	tmpl, err := template.ParseFiles(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("parsing browse template %s: %w", templatePath, err)
+	}
+	return &Browse{tmpl: tmpl}, nil
+}
+
+// ServeHTTP renders an HTML listing of the directory named by r.URL.Path,
+// honoring the same IgnoreHidden/AllowedExts filtering as listFiles and
+// ?sort=name|size|time&order=asc|desc.
+func (b *Browse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	baseDir, err := os.Getwd()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reqPath := strings.TrimPrefix(r.URL.Path, "/")
+	dirPath, err := SafeResolve(baseDir, reqPath)
+	if err != nil {
+		log.Printf("browse: error resolving %q: %v", reqPath, err)
+		if err == ErrOutsideRoot {
+			http.Error(w, "Invalid path", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	info, err := os.Stat(dirPath)
+	if err != nil || !info.IsDir() {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	effective := EffectiveConfig(baseDir, dirPath)
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = "name"
+	}
+	order := r.URL.Query().Get("order")
+	if order == "" {
+		order = "asc"
+	}
+
+	var rows []browseEntry
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if *effective.IgnoreHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.IsDir() && (name == "node_modules" || name == "build" || name == "dist") {
+			continue
+		}
+
+		entryInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if !entry.IsDir() {
+			ext := strings.ToLower(filepath.Ext(name))
+			allowed := false
+			for _, allowedExt := range effective.AllowedExts {
+				if ext == allowedExt {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				continue
+			}
+		}
+
+		rows = append(rows, browseEntry{
+			Name:      name,
+			Path:      escapedHref(reqPath, name),
+			IsDir:     entry.IsDir(),
+			Size:      humanSize(entryInfo.Size()),
+			sizeBytes: entryInfo.Size(),
+			ModTime:   entryInfo.ModTime(),
+		})
+	}
+
+	sortEntries(rows, sortBy, order)
+
+	view := listing{
+		Title:       "/" + reqPath,
+		Breadcrumbs: breadcrumbsFor(reqPath),
+		Entries:     rows,
+		CanGoUp:     reqPath != "",
+		sort:        sortBy,
+		order:       order,
+	}
+	if view.CanGoUp {
+		view.Up = escapedHref(filepath.Dir(reqPath), "")
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := b.tmpl.Execute(w, view); err != nil {
+		log.Printf("browse: template execution failed: %v", err)
+	}
+}
+
+// escapedHref builds the href for an entry inside dir, percent-encoding
+// characters like '?' and '#' that would otherwise be misread as URL
+// syntax. The result is a plain string, not HTML-escaped: it is rendered
+// into an href="{{...}}" attribute, and html/template already applies the
+// correct URL-attribute escaping (e.g. '&' to '&amp;') for that context —
+// escaping it here too would double-encode entities.
+func escapedHref(dir, name string) string {
+	u := url.URL{Path: "/" + strings.TrimPrefix(filepath.Join(dir, name), "/")}
+	return u.String()
+}
+
+func breadcrumbsFor(reqPath string) []breadcrumb {
+	crumbs := []breadcrumb{{Name: "root", Path: "/"}}
+	if reqPath == "" {
+		return crumbs
+	}
+
+	var accumulated string
+	for _, segment := range strings.Split(reqPath, "/") {
+		accumulated = filepath.Join(accumulated, segment)
+		crumbs = append(crumbs, breadcrumb{Name: segment, Path: escapedHref(accumulated, "")})
+	}
+	return crumbs
+}
+
+func sortEntries(entries []browseEntry, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "size":
+			return entries[i].sizeBytes < entries[j].sizeBytes
+		case "time":
+			return entries[i].ModTime.Before(entries[j].ModTime)
+		default:
+			return entries[i].Name < entries[j].Name
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		if order == "desc" {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
+
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// prefersHTML reports whether the request's Accept header lists text/html,
+// used to decide whether to fall back to Browse. Quality values and the
+// order of the listed media types are not considered.
+func prefersHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// reactBuildAvailable reports whether the configured static directory has a
+// built React app to serve.
+func reactBuildAvailable() bool {
+	_, err := os.Stat(filepath.Join(config.StaticDir, "index.html"))
+	return err == nil
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dirConfigFileName is the per-directory override file, modeled on
+// gohttpserver's ".ghs.yml" convention.
+const dirConfigFileName = ".fileserver.yml"
+
+// AuthConfig is the optional per-directory access block.
+type AuthConfig struct {
+	Users  map[string]string `json:"users,omitempty"`
+	Public bool              `json:"public"`
+}
+
+// DirConfig holds the fields a ".fileserver.yml" file may override for the
+// directory it lives in (and, once merged, everything beneath it).
+type DirConfig struct {
+	AllowedExts  []string    `json:"allowedExts,omitempty"`
+	IgnoreHidden *bool       `json:"ignoreHidden,omitempty"`
+	ReadOnly     *bool       `json:"readOnly,omitempty"`
+	Title        string      `json:"title,omitempty"`
+	Auth         *AuthConfig `json:"auth,omitempty"`
+}
+
+// dirConfigCacheEntry pairs a parsed DirConfig with the mtime it was parsed
+// from, so a changed file on disk invalidates the cached entry.
+type dirConfigCacheEntry struct {
+	cfg   DirConfig
+	mtime time.Time
+}
+
+// dirConfigStore caches parsed ".fileserver.yml" files by directory,
+// invalidated by mtime, so merging the chain down to a deep directory does
+// not re-read and re-parse on every request.
+type dirConfigStore struct {
+	mu    sync.RWMutex
+	cache map[string]dirConfigCacheEntry
+}
+
+var dirConfigs = &dirConfigStore{cache: make(map[string]dirConfigCacheEntry)}
+
+// configFor loads (or returns the cached parse of) the ".fileserver.yml" in
+// dir, or the zero DirConfig if the directory has none.
+func (s *dirConfigStore) configFor(dir string) DirConfig {
+	path := filepath.Join(dir, dirConfigFileName)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return DirConfig{}
+	}
+
+	s.mu.RLock()
+	entry, ok := s.cache[path]
+	s.mu.RUnlock()
+	if ok && entry.mtime.Equal(info.ModTime()) {
+		return entry.cfg
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("dirconfig: failed to read %s: %v", path, err)
+		return DirConfig{}
+	}
+
+	cfg, err := parseDirConfig(data)
+	if err != nil {
+		log.Printf("dirconfig: failed to parse %s: %v", path, err)
+		return DirConfig{}
+	}
+
+	s.mu.Lock()
+	s.cache[path] = dirConfigCacheEntry{cfg: cfg, mtime: info.ModTime()}
+	s.mu.Unlock()
+
+	return cfg
+}
+
+// EffectiveConfig walks from baseDir down to targetDir, merging every
+// ".fileserver.yml" found along the way (deeper directories win) on top of
+// the process-wide Config.
+func EffectiveConfig(baseDir, targetDir string) DirConfig {
+	base := DirConfig{
+		AllowedExts:  config.AllowedExts,
+		IgnoreHidden: &config.IgnoreHidden,
+		ReadOnly:     &config.ReadOnly,
+	}
+
+	rel, err := filepath.Rel(baseDir, targetDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return base
+	}
+
+	dir := filepath.Clean(baseDir)
+	merge(&base, dirConfigs.configFor(dir))
+
+	if rel == "." {
+		return base
+	}
+
+	for _, segment := range strings.Split(filepath.ToSlash(rel), "/") {
+		dir = filepath.Join(dir, segment)
+		merge(&base, dirConfigs.configFor(dir))
+	}
+
+	return base
+}
+
+// merge applies every field override set in overlay onto base.
+func merge(base *DirConfig, overlay DirConfig) {
+	if overlay.AllowedExts != nil {
+		base.AllowedExts = overlay.AllowedExts
+	}
+	if overlay.IgnoreHidden != nil {
+		base.IgnoreHidden = overlay.IgnoreHidden
+	}
+	if overlay.ReadOnly != nil {
+		base.ReadOnly = overlay.ReadOnly
+	}
+	if overlay.Title != "" {
+		base.Title = overlay.Title
+	}
+	if overlay.Auth != nil {
+		base.Auth = overlay.Auth
+	}
+}
+
+// parseDirConfig parses a ".fileserver.yml" file. It only understands the
+// small subset of YAML this schema needs (scalars, "- " lists, and one level
+// of nested maps for the "auth" block) rather than pulling in a full YAML
+// parser for five fields.
+func parseDirConfig(data []byte) (DirConfig, error) {
+	var cfg DirConfig
+
+	type line struct {
+		indent int
+		text   string
+	}
+	var lines []line
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		lines = append(lines, line{indent: indent, text: trimmed})
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+
+	unquote := func(s string) string {
+		s = strings.TrimSpace(s)
+		if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+			return s[1 : len(s)-1]
+		}
+		return s
+	}
+
+	i := 0
+	for i < len(lines) {
+		ln := lines[i]
+		if ln.indent != 0 {
+			i++
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(ln.text, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "allowedExts":
+			var exts []string
+			i++
+			for i < len(lines) && lines[i].indent > ln.indent {
+				if ext, ok := strings.CutPrefix(lines[i].text, "- "); ok {
+					exts = append(exts, unquote(ext))
+				}
+				i++
+			}
+			cfg.AllowedExts = exts
+			continue
+
+		case "ignoreHidden":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("ignoreHidden: %w", err)
+			}
+			cfg.IgnoreHidden = &b
+
+		case "readOnly":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return cfg, fmt.Errorf("readOnly: %w", err)
+			}
+			cfg.ReadOnly = &b
+
+		case "title":
+			cfg.Title = unquote(value)
+
+		case "auth":
+			auth := &AuthConfig{}
+			i++
+			for i < len(lines) && lines[i].indent > ln.indent {
+				authLn := lines[i]
+				aKey, aValue, _ := strings.Cut(authLn.text, ":")
+				aKey = strings.TrimSpace(aKey)
+				aValue = strings.TrimSpace(aValue)
+
+				switch aKey {
+				case "public":
+					b, err := strconv.ParseBool(aValue)
+					if err != nil {
+						return cfg, fmt.Errorf("auth.public: %w", err)
+					}
+					auth.Public = b
+					i++
+
+				case "users":
+					users := make(map[string]string)
+					i++
+					for i < len(lines) && lines[i].indent > authLn.indent {
+						uKey, uValue, ok := strings.Cut(lines[i].text, ":")
+						if ok {
+							users[strings.TrimSpace(uKey)] = unquote(uValue)
+						}
+						i++
+					}
+					auth.Users = users
+
+				default:
+					i++
+				}
+			}
+			cfg.Auth = auth
+			continue
+
+		default:
+			// Unknown key: skip it and any nested lines under it.
+			i++
+			for i < len(lines) && lines[i].indent > ln.indent {
+				i++
+			}
+			continue
+		}
+
+		if !hasValue {
+			return cfg, fmt.Errorf("%s: missing value", key)
+		}
+		i++
+	}
+
+	return cfg, nil
+}
+
+// dirConfigView is the subset of DirConfig safe to hand to an
+// unauthenticated client: everything the UI needs to adapt itself, and
+// never the parsed "auth.users" credential map.
+type dirConfigView struct {
+	AllowedExts  []string `json:"allowedExts,omitempty"`
+	IgnoreHidden *bool    `json:"ignoreHidden,omitempty"`
+	ReadOnly     *bool    `json:"readOnly,omitempty"`
+	Title        string   `json:"title,omitempty"`
+	Public       bool     `json:"public"`
+}
+
+// toDirConfigView strips the credential-bearing Auth.Users map out of cfg,
+// keeping only the fields getDirConfig is allowed to publish.
+func toDirConfigView(cfg DirConfig) dirConfigView {
+	view := dirConfigView{
+		AllowedExts:  cfg.AllowedExts,
+		IgnoreHidden: cfg.IgnoreHidden,
+		ReadOnly:     cfg.ReadOnly,
+		Title:        cfg.Title,
+	}
+	if cfg.Auth != nil {
+		view.Public = cfg.Auth.Public
+	}
+	return view
+}
+
+// getDirConfig handles GET /api/config?dir=… returning the effective
+// config for that directory, as a dirConfigView, so the frontend can adapt
+// its UI.
+func getDirConfig(w http.ResponseWriter, r *http.Request) {
+	baseDir, err := os.Getwd()
+	if err != nil {
+		log.Printf("Error getting current directory: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	requestedDir := r.URL.Query().Get("dir")
+	targetDir := baseDir
+	if requestedDir != "" && requestedDir != "." {
+		targetDir = filepath.Join(baseDir, requestedDir)
+	}
+
+	effective := EffectiveConfig(baseDir, targetDir)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	jsonData, err := json.Marshal(toDirConfigView(effective))
+	if err != nil {
+		log.Printf("Error encoding config: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(jsonData)
+}
@@ -0,0 +1,71 @@
+package jsonpath
+
+// Path is a parsed JSONPath expression: a root marker followed by zero or
+// more segments applied left to right.
+type Path struct {
+	Segments []Segment
+}
+
+// Segment is one step of a JSONPath expression.
+type Segment interface {
+	isSegment()
+}
+
+// NameSegment selects a single object field, written as ".name" or
+// "['name']".
+type NameSegment struct {
+	Name string
+}
+
+// IndexSegment selects a single array element, including negative indices
+// counted from the end.
+type IndexSegment struct {
+	Index int
+}
+
+// SliceSegment selects a range of array elements, "[start:end:step]". A nil
+// field means that part of the slice was omitted.
+type SliceSegment struct {
+	Start *int
+	End   *int
+	Step  *int
+}
+
+// WildcardSegment selects every child of an object or array, "*" or "[*]".
+type WildcardSegment struct{}
+
+// RecursiveSegment descends into every nested object/array, "..".
+type RecursiveSegment struct{}
+
+// UnionSegment selects several indices and/or names at once, e.g.
+// "[0,2,5]" or "['a','b']".
+type UnionSegment struct {
+	Indices []int
+	Names   []string
+}
+
+// FilterSegment keeps array elements matching Predicate, "[?(@.price < 10)]".
+// Unlike WildcardSegment and RecursiveSegment, it does not also descend into
+// object values — applying it to a non-array node yields no matches.
+type FilterSegment struct {
+	Predicate *FilterExpr
+}
+
+func (NameSegment) isSegment()      {}
+func (IndexSegment) isSegment()     {}
+func (SliceSegment) isSegment()     {}
+func (WildcardSegment) isSegment()  {}
+func (RecursiveSegment) isSegment() {}
+func (UnionSegment) isSegment()     {}
+func (FilterSegment) isSegment()    {}
+
+// FilterExpr is a boolean expression evaluated against "@", the current
+// array element. It is either a logical combination of two sub-expressions
+// (And/Or set) or a leaf comparison (Op/Field/Literal set).
+type FilterExpr struct {
+	And, Or    *[2]*FilterExpr
+	Op         string // "==", "!=", "<", "<=", ">", ">="
+	Field      string // path after "@.", e.g. "price" or "a.b"
+	FieldIsSet bool   // leaf has no operator: bare "@.field", true when the field exists and is truthy
+	Literal    interface{}
+}
@@ -0,0 +1,319 @@
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Result is one matched value and the concrete path it was found at (e.g.
+// "$.items[2].name"), returned alongside the ?explain=1 output.
+type Result struct {
+	Value interface{}
+	Path  string
+}
+
+type node struct {
+	value interface{}
+	path  string
+}
+
+// Evaluate parses expr and walks root, returning every matching node.
+func Evaluate(root interface{}, expr string) ([]Result, error) {
+	path, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []node{{value: root, path: "$"}}
+	for _, seg := range path.Segments {
+		nodes = applySegment(nodes, seg)
+		if len(nodes) == 0 {
+			break
+		}
+	}
+
+	results := make([]Result, len(nodes))
+	for i, n := range nodes {
+		results[i] = Result{Value: n.value, Path: n.path}
+	}
+	return results, nil
+}
+
+func applySegment(nodes []node, seg Segment) []node {
+	var out []node
+
+	for _, n := range nodes {
+		switch s := seg.(type) {
+		case NameSegment:
+			if m, ok := n.value.(map[string]interface{}); ok {
+				if v, ok := m[s.Name]; ok {
+					out = append(out, node{value: v, path: fmt.Sprintf("%s.%s", n.path, s.Name)})
+				}
+			}
+
+		case IndexSegment:
+			if arr, ok := n.value.([]interface{}); ok {
+				if idx, ok := normalizeIndex(s.Index, len(arr)); ok {
+					out = append(out, node{value: arr[idx], path: fmt.Sprintf("%s[%d]", n.path, idx)})
+				}
+			}
+
+		case SliceSegment:
+			if arr, ok := n.value.([]interface{}); ok {
+				for _, idx := range sliceIndices(s, len(arr)) {
+					out = append(out, node{value: arr[idx], path: fmt.Sprintf("%s[%d]", n.path, idx)})
+				}
+			}
+
+		case WildcardSegment:
+			out = append(out, wildcardChildren(n)...)
+
+		case RecursiveSegment:
+			out = append(out, descendants(n)...)
+
+		case UnionSegment:
+			if arr, ok := n.value.([]interface{}); ok {
+				for _, i := range s.Indices {
+					if idx, ok := normalizeIndex(i, len(arr)); ok {
+						out = append(out, node{value: arr[idx], path: fmt.Sprintf("%s[%d]", n.path, idx)})
+					}
+				}
+			}
+			if m, ok := n.value.(map[string]interface{}); ok {
+				for _, name := range s.Names {
+					if v, ok := m[name]; ok {
+						out = append(out, node{value: v, path: fmt.Sprintf("%s.%s", n.path, name)})
+					}
+				}
+			}
+
+		case FilterSegment:
+			if arr, ok := n.value.([]interface{}); ok {
+				for i, elem := range arr {
+					if evalFilter(s.Predicate, elem) {
+						out = append(out, node{value: elem, path: fmt.Sprintf("%s[%d]", n.path, i)})
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// normalizeIndex resolves a (possibly negative) JSONPath index against an
+// array of the given length, returning ok=false if it is out of bounds.
+func normalizeIndex(idx, length int) (int, bool) {
+	if idx < 0 {
+		idx += length
+	}
+	if idx < 0 || idx >= length {
+		return 0, false
+	}
+	return idx, true
+}
+
+// sliceIndices expands a SliceSegment into the concrete indices it selects,
+// following Python's slicing semantics (the clearest existing precedent for
+// start/end/step with negative values and omitted bounds).
+func sliceIndices(s SliceSegment, length int) []int {
+	step := 1
+	if s.Step != nil {
+		step = *s.Step
+	}
+	if step == 0 {
+		return nil
+	}
+
+	var start, end int
+	if step > 0 {
+		start, end = 0, length
+	} else {
+		start, end = length-1, -1
+	}
+
+	if s.Start != nil {
+		start = clampIndex(*s.Start, length, step > 0)
+	}
+	if s.End != nil {
+		end = clampIndex(*s.End, length, step > 0)
+	}
+
+	var indices []int
+	if step > 0 {
+		for i := start; i < end; i += step {
+			indices = append(indices, i)
+		}
+	} else {
+		for i := start; i > end; i += step {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func clampIndex(idx, length int, forward bool) int {
+	if idx < 0 {
+		idx += length
+	}
+	if forward {
+		if idx < 0 {
+			return 0
+		}
+		if idx > length {
+			return length
+		}
+		return idx
+	}
+	if idx < -1 {
+		return -1
+	}
+	if idx >= length {
+		return length - 1
+	}
+	return idx
+}
+
+func wildcardChildren(n node) []node {
+	var out []node
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = append(out, node{value: v[k], path: fmt.Sprintf("%s.%s", n.path, k)})
+		}
+	case []interface{}:
+		for i, elem := range v {
+			out = append(out, node{value: elem, path: fmt.Sprintf("%s[%d]", n.path, i)})
+		}
+	}
+	return out
+}
+
+// descendants returns n itself plus every node nested within it, so that a
+// following segment (e.g. the NameSegment in "$..price") can filter the set
+// down to actual matches.
+func descendants(n node) []node {
+	out := []node{n}
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := node{value: v[k], path: fmt.Sprintf("%s.%s", n.path, k)}
+			out = append(out, descendants(child)...)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			child := node{value: elem, path: fmt.Sprintf("%s[%d]", n.path, i)}
+			out = append(out, descendants(child)...)
+		}
+	}
+	return out
+}
+
+func evalFilter(expr *FilterExpr, current interface{}) bool {
+	if expr.Or != nil {
+		return evalFilter(expr.Or[0], current) || evalFilter(expr.Or[1], current)
+	}
+	if expr.And != nil {
+		return evalFilter(expr.And[0], current) && evalFilter(expr.And[1], current)
+	}
+
+	value, ok := lookupField(current, expr.Field)
+	if expr.FieldIsSet {
+		return ok && truthy(value)
+	}
+	if !ok {
+		return false
+	}
+	return compare(value, expr.Op, expr.Literal)
+}
+
+func lookupField(current interface{}, field string) (interface{}, bool) {
+	value := current
+	for _, part := range strings.Split(field, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case string:
+		return val != ""
+	default:
+		return true
+	}
+}
+
+func compare(value interface{}, op string, literal interface{}) bool {
+	if lf, ok := value.(float64); ok {
+		if rf, ok := literal.(float64); ok {
+			switch op {
+			case "==":
+				return lf == rf
+			case "!=":
+				return lf != rf
+			case "<":
+				return lf < rf
+			case "<=":
+				return lf <= rf
+			case ">":
+				return lf > rf
+			case ">=":
+				return lf >= rf
+			}
+		}
+		return false
+	}
+
+	if ls, ok := value.(string); ok {
+		if rs, ok := literal.(string); ok {
+			switch op {
+			case "==":
+				return ls == rs
+			case "!=":
+				return ls != rs
+			case "<":
+				return ls < rs
+			case "<=":
+				return ls <= rs
+			case ">":
+				return ls > rs
+			case ">=":
+				return ls >= rs
+			}
+		}
+		return false
+	}
+
+	switch op {
+	case "==":
+		return value == literal
+	case "!=":
+		return value != literal
+	}
+	return false
+}
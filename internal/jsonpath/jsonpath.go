@@ -0,0 +1,69 @@
+// Package jsonpath implements a practical subset of RFC 9535 JSONPath over
+// the interface{} trees produced by encoding/json: root, child access,
+// array indices and slices, wildcards, recursive descent, unions, and
+// filter expressions.
+package jsonpath
+
+import "strings"
+
+// LooksLikeJSONPath reports whether expr uses JSONPath syntax rather than
+// the server's legacy dotted-path syntax ("a.b[0].c"). Callers that need to
+// stay backward compatible should fall back to the legacy evaluator when
+// this returns false.
+func LooksLikeJSONPath(expr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(expr), "$") ||
+		strings.Contains(expr, "[?") ||
+		strings.Contains(expr, "..")
+}
+
+// FromLegacy rewrites a legacy dotted path ("a.b[0].c") into the equivalent
+// JSONPath expression ("$['a']['b'][0]['c']") so both syntaxes can share one
+// evaluator. Each name is emitted as a quoted "['name']" segment rather than
+// a bare ".name" one, since the legacy syntax allows field names (e.g.
+// "feature-flags") that the unquoted JSONPath identifier grammar rejects.
+func FromLegacy(expr string) string {
+	if expr == "" {
+		return "$"
+	}
+	if strings.HasPrefix(expr, "$") {
+		return expr
+	}
+
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, part := range strings.Split(expr, ".") {
+		if part == "" {
+			continue
+		}
+		name, brackets := splitLegacyBrackets(part)
+		if name != "" {
+			sb.WriteString("['")
+			sb.WriteString(strings.ReplaceAll(name, "'", "\\'"))
+			sb.WriteString("']")
+		}
+		for _, b := range brackets {
+			sb.WriteString(b)
+		}
+	}
+	return sb.String()
+}
+
+// splitLegacyBrackets splits a legacy path segment like "items[0]" into its
+// field name and the "[...]" suffixes that followed it.
+func splitLegacyBrackets(part string) (name string, brackets []string) {
+	i := strings.IndexByte(part, '[')
+	if i == -1 {
+		return part, nil
+	}
+
+	name, rest := part[:i], part[i:]
+	for len(rest) > 0 && rest[0] == '[' {
+		end := strings.IndexByte(rest, ']')
+		if end == -1 {
+			break
+		}
+		brackets = append(brackets, rest[:end+1])
+		rest = rest[end+1:]
+	}
+	return name, brackets
+}
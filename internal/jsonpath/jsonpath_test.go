@@ -0,0 +1,147 @@
+package jsonpath
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testRoot() interface{} {
+	return map[string]interface{}{
+		"a": "A",
+		"items": []interface{}{
+			map[string]interface{}{"name": "n0", "price": 5.0, "tag": "x"},
+			map[string]interface{}{"name": "n1", "price": 15.0, "tag": "y"},
+			map[string]interface{}{"name": "n2", "price": 8.0, "tag": "x"},
+		},
+		"nested": map[string]interface{}{
+			"name": "deep",
+		},
+	}
+}
+
+func valuesOf(results []Result) []interface{} {
+	values := make([]interface{}, len(results))
+	for i, r := range results {
+		values[i] = r.Value
+	}
+	return values
+}
+
+func TestEvaluate(t *testing.T) {
+	cases := []struct {
+		name string
+		expr string
+		want []interface{}
+	}{
+		{"root", "$", []interface{}{testRoot()}},
+		{"child", "$.a", []interface{}{"A"}},
+		{"bracket name", "$['a']", []interface{}{"A"}},
+		{"index", "$.items[0].name", []interface{}{"n0"}},
+		{"negative index", "$.items[-1].name", []interface{}{"n2"}},
+		{"slice", "$.items[0:2].name", []interface{}{"n0", "n1"}},
+		{"slice with step", "$.items[0:3:2].name", []interface{}{"n0", "n2"}},
+		{"wildcard array", "$.items[*].name", []interface{}{"n0", "n1", "n2"}},
+		{"wildcard object", "$.nested.*", []interface{}{"deep"}},
+		{"recursive descent", "$..name", []interface{}{"n0", "n1", "n2", "deep"}},
+		{"union indices", "$.items[0,2].name", []interface{}{"n0", "n2"}},
+		{"union names", "$['a','missing']", []interface{}{"A"}},
+		{"filter simple", "$.items[?(@.tag==\"x\")].name", []interface{}{"n0", "n2"}},
+		{"filter and", "$.items[?(@.price < 10 && @.tag==\"x\")].name", []interface{}{"n0", "n2"}},
+		{"filter or", "$.items[?(@.tag==\"y\" || @.price < 6)].name", []interface{}{"n0", "n1"}},
+		{"filter existence", "$.items[?(@.tag)].name", []interface{}{"n0", "n1", "n2"}},
+		{"no match", "$.items[?(@.tag==\"z\")]", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := Evaluate(testRoot(), tc.expr)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) returned error: %v", tc.expr, err)
+			}
+			got := valuesOf(results)
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Evaluate(%q) = %#v, want %#v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMalformed(t *testing.T) {
+	cases := []struct {
+		name string
+		expr string
+	}{
+		{"missing root", "a.b"},
+		{"unterminated bracket", "$.items["},
+		{"unknown operator", "$.items[?(@.price <> 1)]"},
+		{"unterminated string", "$.items[?(@.tag==\"x)]"},
+		{"invalid index", "$.items[abc]"},
+		{"dangling operator", "$.items[?(@.price <)]"},
+		{"unexpected character", "$.items[#]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Evaluate(testRoot(), tc.expr); err == nil {
+				t.Fatalf("Evaluate(%q) expected an error, got none", tc.expr)
+			}
+		})
+	}
+}
+
+func TestFromLegacy(t *testing.T) {
+	cases := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"empty", "", "$"},
+		{"already jsonpath", "$.a.b", "$.a.b"},
+		{"simple dotted", "a.b.c", "$['a']['b']['c']"},
+		{"array index", "items[0].name", "$['items'][0]['name']"},
+		{"dashed key", "feature-flags.enabled", "$['feature-flags']['enabled']"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FromLegacy(tc.expr); got != tc.want {
+				t.Fatalf("FromLegacy(%q) = %q, want %q", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFromLegacyRoundTrips(t *testing.T) {
+	root := map[string]interface{}{
+		"feature-flags": map[string]interface{}{"enabled": true},
+	}
+	results, err := Evaluate(root, FromLegacy("feature-flags.enabled"))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if len(results) != 1 || results[0].Value != true {
+		t.Fatalf("got %#v, want a single true value", results)
+	}
+}
+
+func TestLooksLikeJSONPath(t *testing.T) {
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"$.a.b", true},
+		{"$.items[?(@.price < 1)]", true},
+		{"$..name", true},
+		{"a.b.c", false},
+		{"items[0].name", false},
+	}
+
+	for _, tc := range cases {
+		if got := LooksLikeJSONPath(tc.expr); got != tc.want {
+			t.Errorf("LooksLikeJSONPath(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse compiles a JSONPath expression into a Path. The supported grammar
+// is a practical subset of RFC 9535: root "$", child ".name" / "['name']",
+// array index "[n]" (negative allowed), slices "[start:end:step]",
+// wildcard "*", recursive descent "..", unions "[0,2,5]" / "['a','b']",
+// and filter expressions "[?(@.field op literal)]" combined with
+// "&&"/"||".
+func Parse(expr string) (*Path, error) {
+	lx := newLexer(strings.TrimSpace(expr))
+	toks, err := lx.tokens()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	if p.peek().kind != tokRoot {
+		return nil, fmt.Errorf("jsonpath: expression must start with '$': %q", expr)
+	}
+	p.advance()
+
+	path := &Path{}
+	for p.peek().kind != tokEOF {
+		seg, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		path.Segments = append(path.Segments, seg...)
+	}
+	return path, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.toks) {
+		return token{kind: tokEOF}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.peek()
+	if tok.kind != kind {
+		return token{}, fmt.Errorf("jsonpath: expected %s, got %q", what, tok.text)
+	}
+	return p.advance(), nil
+}
+
+// parseSegment returns one or two AST segments: "..name" expands to
+// [RecursiveSegment, NameSegment] so evaluation can reuse the normal
+// child-selection logic after the recursive expansion.
+func (p *parser) parseSegment() ([]Segment, error) {
+	switch p.peek().kind {
+	case tokDot:
+		p.advance()
+		if p.peek().kind == tokStar {
+			p.advance()
+			return []Segment{WildcardSegment{}}, nil
+		}
+		name, err := p.expect(tokIdent, "identifier after '.'")
+		if err != nil {
+			return nil, err
+		}
+		return []Segment{NameSegment{Name: name.text}}, nil
+
+	case tokDotDot:
+		p.advance()
+		if p.peek().kind == tokIdent {
+			name := p.advance()
+			return []Segment{RecursiveSegment{}, NameSegment{Name: name.text}}, nil
+		}
+		if p.peek().kind == tokStar {
+			p.advance()
+			return []Segment{RecursiveSegment{}, WildcardSegment{}}, nil
+		}
+		return []Segment{RecursiveSegment{}}, nil
+
+	case tokLBracket:
+		p.advance()
+		seg, err := p.parseBracket()
+		if err != nil {
+			return nil, err
+		}
+		return []Segment{seg}, nil
+
+	default:
+		return nil, fmt.Errorf("jsonpath: unexpected token %q", p.peek().text)
+	}
+}
+
+func (p *parser) parseBracket() (Segment, error) {
+	switch p.peek().kind {
+	case tokStar:
+		p.advance()
+		if _, err := p.expect(tokRBracket, "']'"); err != nil {
+			return nil, err
+		}
+		return WildcardSegment{}, nil
+
+	case tokQuestion:
+		p.advance()
+		if _, err := p.expect(tokLParen, "'(' after '?'"); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseFilterOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRBracket, "']'"); err != nil {
+			return nil, err
+		}
+		return FilterSegment{Predicate: expr}, nil
+
+	case tokColon:
+		return p.parseSlice(nil)
+
+	case tokNumber:
+		n, err := parseIntToken(p.advance())
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind == tokColon {
+			return p.parseSlice(&n)
+		}
+		indices := []int{n}
+		for p.peek().kind == tokComma {
+			p.advance()
+			tok, err := p.expect(tokNumber, "number in index list")
+			if err != nil {
+				return nil, err
+			}
+			v, err := parseIntToken(tok)
+			if err != nil {
+				return nil, err
+			}
+			indices = append(indices, v)
+		}
+		if _, err := p.expect(tokRBracket, "']'"); err != nil {
+			return nil, err
+		}
+		if len(indices) == 1 {
+			return IndexSegment{Index: indices[0]}, nil
+		}
+		return UnionSegment{Indices: indices}, nil
+
+	case tokString:
+		names := []string{p.advance().text}
+		for p.peek().kind == tokComma {
+			p.advance()
+			tok, err := p.expect(tokString, "string in name list")
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, tok.text)
+		}
+		if _, err := p.expect(tokRBracket, "']'"); err != nil {
+			return nil, err
+		}
+		if len(names) == 1 {
+			return NameSegment{Name: names[0]}, nil
+		}
+		return UnionSegment{Names: names}, nil
+
+	default:
+		return nil, fmt.Errorf("jsonpath: unexpected token %q inside '[...]'", p.peek().text)
+	}
+}
+
+// parseSlice parses "[start:end:step]" having already consumed the start
+// number, if any (start is nil when the slice begins with ':').
+func (p *parser) parseSlice(start *int) (Segment, error) {
+	if _, err := p.expect(tokColon, "':'"); err != nil {
+		return nil, err
+	}
+
+	seg := SliceSegment{Start: start}
+
+	if p.peek().kind == tokNumber {
+		v, err := parseIntToken(p.advance())
+		if err != nil {
+			return nil, err
+		}
+		seg.End = &v
+	}
+
+	if p.peek().kind == tokColon {
+		p.advance()
+		if p.peek().kind == tokNumber {
+			v, err := parseIntToken(p.advance())
+			if err != nil {
+				return nil, err
+			}
+			seg.Step = &v
+		}
+	}
+
+	if _, err := p.expect(tokRBracket, "']'"); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+func parseIntToken(tok token) (int, error) {
+	v, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, fmt.Errorf("jsonpath: invalid integer %q", tok.text)
+	}
+	return v, nil
+}
+
+// parseFilterOr / parseFilterAnd implement the usual precedence of "||"
+// binding looser than "&&".
+func (p *parser) parseFilterOr() (*FilterExpr, error) {
+	left, err := p.parseFilterAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.advance()
+		right, err := p.parseFilterAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterExpr{Or: &[2]*FilterExpr{left, right}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFilterAnd() (*FilterExpr, error) {
+	left, err := p.parseFilterComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.advance()
+		right, err := p.parseFilterComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterExpr{And: &[2]*FilterExpr{left, right}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFilterComparison() (*FilterExpr, error) {
+	if _, err := p.expect(tokCurrent, "'@' in filter expression"); err != nil {
+		return nil, err
+	}
+
+	var field strings.Builder
+	for p.peek().kind == tokDot {
+		p.advance()
+		name, err := p.expect(tokIdent, "identifier after '.' in filter expression")
+		if err != nil {
+			return nil, err
+		}
+		if field.Len() > 0 {
+			field.WriteByte('.')
+		}
+		field.WriteString(name.text)
+	}
+
+	if p.peek().kind != tokOp {
+		return &FilterExpr{Field: field.String(), FieldIsSet: true}, nil
+	}
+
+	op := p.advance().text
+	literal, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	return &FilterExpr{Op: op, Field: field.String(), Literal: literal}, nil
+}
+
+func (p *parser) parseLiteral() (interface{}, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNumber:
+		p.advance()
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid number literal %q", tok.text)
+		}
+		return f, nil
+	case tokString:
+		p.advance()
+		return tok.text, nil
+	case tokIdent:
+		p.advance()
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("jsonpath: invalid literal %q", tok.text)
+	default:
+		return nil, fmt.Errorf("jsonpath: expected literal, got %q", tok.text)
+	}
+}
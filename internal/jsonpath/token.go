@@ -0,0 +1,229 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF      tokenKind = iota
+	tokRoot               // $
+	tokCurrent            // @
+	tokDot                // .
+	tokDotDot             // ..
+	tokLBracket           // [
+	tokRBracket           // ]
+	tokLParen             // (
+	tokRParen             // )
+	tokComma              // ,
+	tokColon              // :
+	tokStar               // *
+	tokQuestion           // ?
+	tokIdent              // name
+	tokNumber             // 123, -1, 3.14
+	tokString             // 'x' or "x"
+	tokAnd                // &&
+	tokOr                 // ||
+	tokOp                 // == != < > <= >=
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lexer turns a JSONPath expression into a flat token stream. It is
+// deliberately small: JSONPath's grammar is simple enough that a
+// hand-written scanner is clearer than pulling in a parser generator.
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(expr string) *lexer {
+	return &lexer{input: []rune(expr)}
+}
+
+func (l *lexer) peekRune() (rune, bool) {
+	if l.pos >= len(l.input) {
+		return 0, false
+	}
+	return l.input[l.pos], true
+}
+
+func (l *lexer) tokens() ([]token, error) {
+	var toks []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		toks = append(toks, tok)
+		if tok.kind == tokEOF {
+			return toks, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+
+	r, ok := l.peekRune()
+	if !ok {
+		return token{kind: tokEOF}, nil
+	}
+
+	switch r {
+	case '$':
+		l.pos++
+		return token{kind: tokRoot, text: "$"}, nil
+	case '@':
+		l.pos++
+		return token{kind: tokCurrent, text: "@"}, nil
+	case '[':
+		l.pos++
+		return token{kind: tokLBracket, text: "["}, nil
+	case ']':
+		l.pos++
+		return token{kind: tokRBracket, text: "]"}, nil
+	case '(':
+		l.pos++
+		return token{kind: tokLParen, text: "("}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")"}, nil
+	case ',':
+		l.pos++
+		return token{kind: tokComma, text: ","}, nil
+	case ':':
+		l.pos++
+		return token{kind: tokColon, text: ":"}, nil
+	case '*':
+		l.pos++
+		return token{kind: tokStar, text: "*"}, nil
+	case '?':
+		l.pos++
+		return token{kind: tokQuestion, text: "?"}, nil
+	case '.':
+		l.pos++
+		if r2, ok := l.peekRune(); ok && r2 == '.' {
+			l.pos++
+			return token{kind: tokDotDot, text: ".."}, nil
+		}
+		return token{kind: tokDot, text: "."}, nil
+	case '\'', '"':
+		return l.lexString(r)
+	case '&':
+		l.pos++
+		if r2, ok := l.peekRune(); ok && r2 == '&' {
+			l.pos++
+			return token{kind: tokAnd, text: "&&"}, nil
+		}
+		return token{}, fmt.Errorf("jsonpath: unexpected '&' at position %d", l.pos)
+	case '|':
+		l.pos++
+		if r2, ok := l.peekRune(); ok && r2 == '|' {
+			l.pos++
+			return token{kind: tokOr, text: "||"}, nil
+		}
+		return token{}, fmt.Errorf("jsonpath: unexpected '|' at position %d", l.pos)
+	case '=', '!', '<', '>':
+		return l.lexOp()
+	}
+
+	if r == '-' || isDigit(r) {
+		return l.lexNumber()
+	}
+	if isIdentStart(r) {
+		return l.lexIdent()
+	}
+
+	return token{}, fmt.Errorf("jsonpath: unexpected character %q at position %d", r, l.pos)
+}
+
+func (l *lexer) skipSpace() {
+	for {
+		r, ok := l.peekRune()
+		if !ok || !strings.ContainsRune(" \t\n\r", r) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) lexString(quote rune) (token, error) {
+	l.pos++ // consume opening quote
+	var sb strings.Builder
+	for {
+		r, ok := l.peekRune()
+		if !ok {
+			return token{}, fmt.Errorf("jsonpath: unterminated string literal")
+		}
+		l.pos++
+		if r == quote {
+			return token{kind: tokString, text: sb.String()}, nil
+		}
+		if r == '\\' {
+			if r2, ok := l.peekRune(); ok {
+				l.pos++
+				sb.WriteRune(r2)
+				continue
+			}
+		}
+		sb.WriteRune(r)
+	}
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	l.pos++ // consume leading '-' or first digit
+	for {
+		r, ok := l.peekRune()
+		if !ok || !(isDigit(r) || r == '.') {
+			break
+		}
+		l.pos++
+	}
+	return token{kind: tokNumber, text: string(l.input[start:l.pos])}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for {
+		r, ok := l.peekRune()
+		if !ok || !isIdentPart(r) {
+			break
+		}
+		l.pos++
+	}
+	return token{kind: tokIdent, text: string(l.input[start:l.pos])}, nil
+}
+
+func (l *lexer) lexOp() (token, error) {
+	start := l.pos
+	r, _ := l.peekRune()
+	l.pos++
+	if r == '=' || r == '!' || r == '<' || r == '>' {
+		if r2, ok := l.peekRune(); ok && r2 == '=' {
+			l.pos++
+		}
+	}
+	text := string(l.input[start:l.pos])
+	switch text {
+	case "==", "!=", "<", ">", "<=", ">=":
+		return token{kind: tokOp, text: text}, nil
+	}
+	return token{}, fmt.Errorf("jsonpath: invalid operator %q", text)
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || isDigit(r)
+}
@@ -2,16 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/criswit/fileserver/internal/jsonpath"
 )
 
 // Configuration options
@@ -36,11 +39,16 @@ type FileInfo struct {
 // Global configuration
 var config Config
 
+// Global search index, populated by the background Indexer started in main.
+var indexer *Indexer
+
 func main() {
 	// Parse command line flags
 	port := flag.String("port", "8080", "Port to run the server on")
 	staticDir := flag.String("static", "./frontend/build", "Directory containing static files")
 	readOnly := flag.Bool("readonly", true, "Run in read-only mode")
+	browseFlag := flag.Bool("browse", false, "Serve an HTML directory listing when the React build is absent")
+	browseTemplate := flag.String("browse-template", "", "Path to a custom template for -browse (defaults to the built-in template)")
 	flag.Parse()
 
 	// Set up configuration
@@ -58,6 +66,10 @@ func main() {
 	log.Printf("Current working directory at startup: %s", cwd)
 	log.Printf("Static files directory: %s (absolute: %s)", *staticDir, absStaticDir)
 
+	// Start the background full-text indexer over the current working directory
+	indexer = NewIndexer(cwd)
+	indexer.Start()
+
 	// Set up middleware
 	mux := http.NewServeMux()
 
@@ -74,18 +86,31 @@ func main() {
 	absStaticDir, _ = filepath.Abs(config.StaticDir)
 	log.Printf("Serving static files from directory: %s", absStaticDir)
 	fs := http.FileServer(http.Dir(absStaticDir))
-	mux.Handle("/", fs)
+
+	var browse *Browse
+	if *browseFlag {
+		b, err := NewBrowse(*browseTemplate)
+		if err != nil {
+			log.Fatalf("Error loading browse template: %v", err)
+		}
+		browse = b
+		log.Printf("HTML browse mode enabled")
+	}
+	mux.Handle("/", rootHandler(fs, browse))
 
 	// API endpoints
 	mux.HandleFunc("/api/files", listFiles)
-	mux.HandleFunc("/api/content/", getFileContent)
+	mux.HandleFunc("/api/content/", contentHandler)
 	mux.HandleFunc("/api/query/", queryJSON)
+	mux.HandleFunc("/api/search", searchFiles)
+	mux.HandleFunc("/api/config", getDirConfig)
+	mux.HandleFunc("/api/move", moveFile)
 
 	// Add CORS headers for development
 	corsMiddleware := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 			if r.Method == "OPTIONS" {
@@ -106,6 +131,19 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+config.Port, handler))
 }
 
+// rootHandler serves the React build at "/" as before, falling back to an
+// HTML directory listing when browse mode is enabled, the client prefers
+// HTML, and no React build is present to serve instead.
+func rootHandler(fs http.Handler, browse *Browse) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if browse != nil && prefersHTML(r) && !reactBuildAvailable() {
+			browse.ServeHTTP(w, r)
+			return
+		}
+		fs.ServeHTTP(w, r)
+	})
+}
+
 func modeName() string {
 	if config.ReadOnly {
 		return "read-only"
@@ -115,8 +153,6 @@ func modeName() string {
 
 func listFiles(w http.ResponseWriter, r *http.Request) {
 	requestedDir := r.URL.Query().Get("dir")
-	var rootDir string
-	var err error
 
 	// Get the base directory (current working directory)
 	baseDir, err := os.Getwd()
@@ -126,23 +162,30 @@ func listFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle directory path
-	if requestedDir == "" || requestedDir == "." {
-		rootDir = baseDir
-	} else {
-		// Check if path is absolute
-		if filepath.IsAbs(requestedDir) {
-			rootDir = requestedDir
+	// Resolve strictly under baseDir, same as getFileContent and
+	// queryJSON — no absolute paths, no escaping the root via "..".
+	rootDir, err := SafeResolve(baseDir, requestedDir)
+	if err != nil {
+		log.Printf("Error resolving directory %s: %v", requestedDir, err)
+		if errors.Is(err, ErrNotFound) {
+			http.Error(w, fmt.Sprintf("Directory not found: %s", requestedDir), http.StatusNotFound)
+		} else if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid directory path", http.StatusBadRequest)
 		} else {
-			// If it's relative, join with baseDir
-			rootDir = filepath.Join(baseDir, requestedDir)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
+		return
 	}
 
 	log.Printf("Current working directory: %s", baseDir)
 	log.Printf("Requested directory: %s", requestedDir)
 	log.Printf("Listing files in directory: %s", rootDir)
 
+	// Merge any .fileserver.yml overrides found between baseDir and rootDir.
+	effective := EffectiveConfig(baseDir, rootDir)
+	allowedExts := effective.AllowedExts
+	ignoreHidden := *effective.IgnoreHidden
+
 	// Validate the directory exists
 	fileInfo, err := os.Stat(rootDir)
 	if err != nil {
@@ -180,7 +223,7 @@ func listFiles(w http.ResponseWriter, r *http.Request) {
 		entryPath := filepath.Join(rootDir, entryName)
 
 		// Skip hidden files and directories if configured
-		if config.IgnoreHidden && strings.HasPrefix(entryName, ".") {
+		if ignoreHidden && strings.HasPrefix(entryName, ".") {
 			continue
 		}
 
@@ -202,7 +245,7 @@ func listFiles(w http.ResponseWriter, r *http.Request) {
 		if !entry.IsDir() {
 			ext := strings.ToLower(filepath.Ext(entryName))
 			isAllowedExt := false
-			for _, allowedExt := range config.AllowedExts {
+			for _, allowedExt := range allowedExts {
 				if ext == allowedExt {
 					isAllowedExt = true
 					break
@@ -225,7 +268,7 @@ func listFiles(w http.ResponseWriter, r *http.Request) {
 				for _, subEntry := range subEntries {
 					if !subEntry.IsDir() {
 						ext := strings.ToLower(filepath.Ext(subEntry.Name()))
-						for _, allowedExt := range config.AllowedExts {
+						for _, allowedExt := range allowedExts {
 							if ext == allowedExt {
 								hasRelevantFiles = true
 								break
@@ -292,7 +335,7 @@ func listFiles(w http.ResponseWriter, r *http.Request) {
 }
 
 func getFileContent(w http.ResponseWriter, r *http.Request) {
-	requestedFile := strings.TrimPrefix(r.URL.Path, "/api/content/")
+	requestedFile := path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/api/content/"))
 	requestedDir := r.URL.Query().Get("dir")
 
 	log.Printf("Getting content for file: %s in directory: %s", requestedFile, requestedDir)
@@ -305,96 +348,41 @@ func getFileContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Attempt multiple resolution strategies
-	var filePaths []string
-
-	// If directory parameter is provided, try that first with the filename
-	if requestedDir != "" {
-		// Create a full path by joining the current directory, the requested directory, and the file name
-		filePaths = append(filePaths, filepath.Join(baseDir, requestedDir, requestedFile))
-	}
-
-	// Then try the standard resolution approaches
-	// 1. Check if the path could be a direct path from current working directory
-	filePaths = append(filePaths, filepath.Join(baseDir, requestedFile))
-
-	// 2. Check if the PARENT directory of the file is part of the path
-	// For example, if request is for "package.json" while in "frontend" directory
-	if !strings.Contains(requestedFile, "/") {
-		// First, get all directories
-		entries, err := os.ReadDir(baseDir)
-		if err == nil {
-			for _, entry := range entries {
-				if entry.IsDir() {
-					subDirPath := filepath.Join(baseDir, entry.Name(), requestedFile)
-					filePaths = append(filePaths, subDirPath)
-				}
-			}
-		}
-	}
-
-	// Try each possible path
-	var resolvedPath string
-	for _, path := range filePaths {
-		info, err := os.Stat(path)
-		if err == nil && !info.IsDir() {
-			resolvedPath = path
-			break
-		}
-	}
-
-	// If no matching paths were found and the path has multiple segments
-	// Try direct match against full path
-	if resolvedPath == "" && strings.Contains(requestedFile, "/") {
-		fullPath := filepath.Join(baseDir, requestedFile)
-		info, err := os.Stat(fullPath)
-		if err == nil && !info.IsDir() {
-			resolvedPath = fullPath
+	// Resolve strictly under dir= (or baseDir if dir is unset) — no more
+	// guessing across sibling directories.
+	relPath := filepath.Join(requestedDir, strings.TrimPrefix(requestedFile, "/"))
+	filePath, err := SafeResolve(baseDir, relPath)
+	if err != nil {
+		log.Printf("Error resolving file %s: %v", relPath, err)
+		if errors.Is(err, ErrNotFound) {
+			http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
+		} else if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
-	}
-
-	// If no path was resolved, error out
-	if resolvedPath == "" {
-		log.Printf("Error: Could not resolve file: %s", requestedFile)
-		log.Printf("Attempted paths: %v", filePaths)
-		http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
 		return
 	}
-
-	filePath := resolvedPath
 	log.Printf("Resolved absolute file path: %s", filePath)
 
-	// Basic security check - prevent directory traversal attacks
-	if strings.Contains(filePath, "..") {
-		log.Printf("Security error: path contains prohibited '..' sequence: %s", filePath)
-		http.Error(w, "Invalid file path", http.StatusBadRequest)
-		return
-	}
-
-	// Check file exists
-	fileInfo, err := os.Stat(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("Error: File not found: %s", filePath)
+	if _, err := requireRegularFile(filePath); err != nil {
+		log.Printf("Error: %v: %s", err, filePath)
+		if errors.Is(err, ErrNotFound) {
 			http.Error(w, fmt.Sprintf("File not found: %s", filePath), http.StatusNotFound)
+		} else if errors.Is(err, ErrNotRegular) {
+			http.Error(w, "Cannot display directory content", http.StatusBadRequest)
 		} else {
-			log.Printf("Error accessing file: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 		return
 	}
 
-	// Ensure it's a file, not a directory
-	if fileInfo.IsDir() {
-		log.Printf("Error: Cannot display directory content: %s", filePath)
-		http.Error(w, "Cannot display directory content", http.StatusBadRequest)
-		return
-	}
-
-	// Check file extension
+	// Check file extension against the effective .fileserver.yml-merged
+	// allowlist for the directory the file lives in.
+	effective := EffectiveConfig(baseDir, filepath.Dir(filePath))
 	ext := strings.ToLower(filepath.Ext(filePath))
 	isAllowedExt := false
-	for _, allowedExt := range config.AllowedExts {
+	for _, allowedExt := range effective.AllowedExts {
 		if ext == allowedExt {
 			isAllowedExt = true
 			break
@@ -450,91 +438,35 @@ func queryJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Attempt multiple resolution strategies
-	var filePaths []string
-
-	// If directory parameter is provided, try that first with the filename
-	if requestedDir != "" {
-		// Create a full path by joining the current directory, the requested directory, and the file name
-		filePaths = append(filePaths, filepath.Join(baseDir, requestedDir, requestedFile))
-	}
-
-	// Then try the standard resolution approaches
-	// 1. Check if the path could be a direct path from current working directory
-	filePaths = append(filePaths, filepath.Join(baseDir, requestedFile))
-
-	// 2. Check if the PARENT directory of the file is part of the path
-	// For example, if request is for "package.json" while in "frontend" directory
-	if !strings.Contains(requestedFile, "/") {
-		// First, get all directories
-		entries, err := os.ReadDir(baseDir)
-		if err == nil {
-			for _, entry := range entries {
-				if entry.IsDir() {
-					subDirPath := filepath.Join(baseDir, entry.Name(), requestedFile)
-					filePaths = append(filePaths, subDirPath)
-				}
-			}
-		}
-	}
-
-	// Try each possible path
-	var resolvedPath string
-	for _, path := range filePaths {
-		info, err := os.Stat(path)
-		if err == nil && !info.IsDir() {
-			resolvedPath = path
-			break
-		}
-	}
-
-	// If no matching paths were found and the path has multiple segments
-	// Try direct match against full path
-	if resolvedPath == "" && strings.Contains(requestedFile, "/") {
-		fullPath := filepath.Join(baseDir, requestedFile)
-		info, err := os.Stat(fullPath)
-		if err == nil && !info.IsDir() {
-			resolvedPath = fullPath
+	// Resolve strictly under dir= (or baseDir if dir is unset) — no more
+	// guessing across sibling directories.
+	relPath := filepath.Join(requestedDir, strings.TrimPrefix(path.Clean("/"+requestedFile), "/"))
+	filePath, err := SafeResolve(baseDir, relPath)
+	if err != nil {
+		log.Printf("Error resolving file %s: %v", relPath, err)
+		if errors.Is(err, ErrNotFound) {
+			http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
+		} else if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
-	}
-
-	// If no path was resolved, error out
-	if resolvedPath == "" {
-		log.Printf("Error: Could not resolve file: %s", requestedFile)
-		log.Printf("Attempted paths: %v", filePaths)
-		http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
 		return
 	}
-
-	filePath := resolvedPath
 	log.Printf("Resolved absolute JSON file path: %s", filePath)
 
-	// Basic security check
-	if strings.Contains(filePath, "..") {
-		log.Printf("Security error: path contains prohibited '..' sequence: %s", filePath)
-		http.Error(w, "Invalid file path", http.StatusBadRequest)
-		return
-	}
-
-	// Check file exists and is a JSON file
-	fileInfo, err := os.Stat(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("Error: File not found: %s", filePath)
+	if _, err := requireRegularFile(filePath); err != nil {
+		log.Printf("Error: %v: %s", err, filePath)
+		if errors.Is(err, ErrNotFound) {
 			http.Error(w, fmt.Sprintf("File not found: %s", filePath), http.StatusNotFound)
+		} else if errors.Is(err, ErrNotRegular) {
+			http.Error(w, "Cannot query directory", http.StatusBadRequest)
 		} else {
-			log.Printf("Error accessing file: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 		return
 	}
 
-	if fileInfo.IsDir() {
-		log.Printf("Error: Cannot query directory: %s", filePath)
-		http.Error(w, "Cannot query directory", http.StatusBadRequest)
-		return
-	}
-
 	ext := strings.ToLower(filepath.Ext(filePath))
 	if ext != ".json" {
 		log.Printf("Error: File is not JSON: %s (ext: %s)", filePath, ext)
@@ -558,78 +490,57 @@ func queryJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle array indexing in the query
-	arrayIndexRegex := regexp.MustCompile(`(.*)\[(\d+)\](.*)`)
-
-	// Process the JSON path components
-	parts := strings.Split(jsonPath, ".")
-	result := jsonData
-
-	for _, part := range parts {
-		// Check if this part contains an array index
-		arrayMatches := arrayIndexRegex.FindStringSubmatch(part)
-
-		if len(arrayMatches) > 0 {
-			// It's an array access
-			objKey := arrayMatches[1]   // Part before [index]
-			idxStr := arrayMatches[2]   // The index number
-			restPath := arrayMatches[3] // Anything after [index]
-
-			log.Printf("Processing array access: key=%s, index=%s, rest=%s", objKey, idxStr, restPath)
-
-			// First get the object containing the array
-			if objKey != "" {
-				if m, ok := result.(map[string]interface{}); ok {
-					result = m[objKey]
-				} else {
-					log.Printf("Error: Cannot access property '%s' - not an object", objKey)
-					http.Error(w, fmt.Sprintf("Cannot access property '%s' - not an object", objKey), http.StatusBadRequest)
-					return
-				}
-			}
-
-			// Then access the array element
-			if arr, ok := result.([]interface{}); ok {
-				idx := 0
-				fmt.Sscanf(idxStr, "%d", &idx)
-
-				if idx >= 0 && idx < len(arr) {
-					result = arr[idx]
-				} else {
-					log.Printf("Error: Array index out of bounds: %d (array length: %d)", idx, len(arr))
-					http.Error(w, fmt.Sprintf("Array index out of bounds: %d", idx), http.StatusBadRequest)
-					return
-				}
-			} else {
-				log.Printf("Error: Cannot index - not an array, type is %T", result)
-				http.Error(w, "Cannot index - not an array", http.StatusBadRequest)
-				return
-			}
+	// Accept either a JSONPath expression or the legacy dotted syntax
+	// ("a.b[0].c"), detected by the absence of JSONPath-only syntax.
+	isLegacy := !jsonpath.LooksLikeJSONPath(jsonPath)
+	expr := jsonPath
+	if isLegacy {
+		expr = jsonpath.FromLegacy(expr)
+	}
 
-			// Handle any remainder of the path (currently not supported in this simple implementation)
-			if restPath != "" {
-				log.Printf("Error: Complex array paths not supported: %s", restPath)
-				http.Error(w, "Complex array paths not supported", http.StatusBadRequest)
-				return
-			}
-		} else {
-			// Regular object property access
-			if m, ok := result.(map[string]interface{}); ok {
-				result = m[part]
-				log.Printf("Accessed property '%s'", part)
-			} else {
-				log.Printf("Error: Cannot access property '%s' - not an object, type is %T", part, result)
-				http.Error(w, fmt.Sprintf("Cannot access property '%s' - not an object", part), http.StatusBadRequest)
-				return
-			}
-		}
+	matches, err := jsonpath.Evaluate(jsonData, expr)
+	if err != nil {
+		log.Printf("Error evaluating JSONPath %q: %v", expr, err)
+		http.Error(w, "Invalid path: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	// Return the result
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	jsonResult, err := json.Marshal(result)
+	explain := r.URL.Query().Get("explain") == "1"
+
+	var payload interface{}
+	switch {
+	case explain:
+		type explainMatch struct {
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}
+		explained := make([]explainMatch, len(matches))
+		for i, m := range matches {
+			explained[i] = explainMatch{Path: m.Path, Value: m.Value}
+		}
+		payload = explained
+
+	// A legacy dotted path that finds nothing used to come back as a bare
+	// "m[part]" miss, which queryJSON reported as JSON null; preserve that
+	// for existing clients instead of the new engine's natural "[]" shape.
+	case len(matches) == 0 && isLegacy:
+		payload = nil
+
+	case len(matches) == 1:
+		payload = matches[0].Value
+
+	default:
+		values := make([]interface{}, len(matches))
+		for i, m := range matches {
+			values[i] = m.Value
+		}
+		payload = values
+	}
+
+	jsonResult, err := json.Marshal(payload)
 	if err != nil {
 		log.Printf("Error encoding result to JSON: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
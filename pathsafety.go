@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Typed errors returned by SafeResolve and the handlers that build on it, so
+// callers can distinguish "doesn't exist" from "tried to escape the root"
+// without string-matching error messages.
+var (
+	ErrOutsideRoot = errors.New("path resolves outside the root directory")
+	ErrNotFound    = errors.New("path not found")
+	ErrNotRegular  = errors.New("path is not a regular file")
+)
+
+// SafeResolve joins userPath onto baseDir and returns the resulting absolute
+// path only if it is still rooted inside baseDir once symlinks are
+// resolved. Absolute user paths are rejected unless allowAbsolute is passed
+// and true.
+//
+// This replaces the old "strings.Contains(path, \"..\")" check, which ran
+// after filepath.Join had already cleaned the path (so it could never fire
+// on a legitimate ".." segment) and did nothing to stop a symlink inside the
+// root from pointing back out of it.
+func SafeResolve(baseDir, userPath string, allowAbsolute ...bool) (string, error) {
+	cleanBase := filepath.Clean(baseDir)
+	cleanUser := filepath.Clean(userPath)
+
+	if filepath.IsAbs(cleanUser) && !(len(allowAbsolute) > 0 && allowAbsolute[0]) {
+		return "", ErrOutsideRoot
+	}
+
+	var joined string
+	if filepath.IsAbs(cleanUser) {
+		joined = cleanUser
+	} else {
+		joined = filepath.Join(cleanBase, cleanUser)
+	}
+
+	resolvedBase, err := filepath.EvalSymlinks(cleanBase)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(joined)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	if !isWithinRoot(resolvedBase, resolved) {
+		return "", ErrOutsideRoot
+	}
+
+	return resolved, nil
+}
+
+// isWithinRoot reports whether path is root itself or lexically nested under
+// it, using filepath.Rel rather than a plain string.HasPrefix so that
+// sibling directories sharing a prefix (e.g. "/data" vs "/data2") are not
+// mistaken for children.
+func isWithinRoot(root, path string) bool {
+	if root == path {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// requireRegularFile stats path and translates the result into the typed
+// error set, so handlers that need a file (not a directory) can reuse it
+// after calling SafeResolve.
+func requireRegularFile(path string) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, ErrNotRegular
+	}
+	return info, nil
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// indexInterval controls how often the background indexer rebuilds the
+// in-memory search index, mirroring the periodic-rebuild pattern used by
+// gohttpserver's directory indexer.
+const indexInterval = 10 * time.Minute
+
+// IndexFileItem is a single indexed file: its stat info plus the derived
+// title and token frequencies used for ranking search hits.
+type IndexFileItem struct {
+	Path   string
+	Info   os.FileInfo
+	Title  string
+	Tokens map[string]int
+}
+
+// Indexer walks a base directory building an in-memory full-text index of
+// markdown and JSON files, and keeps it fresh on a ticker so searches never
+// need to touch the filesystem directly.
+type Indexer struct {
+	baseDir string
+
+	mu    sync.RWMutex
+	items []IndexFileItem
+}
+
+// NewIndexer creates an Indexer rooted at baseDir. Call Start to perform the
+// initial walk and begin periodic rebuilds.
+func NewIndexer(baseDir string) *Indexer {
+	return &Indexer{baseDir: baseDir}
+}
+
+// Start performs an initial index build and then rebuilds on a ticker until
+// the process exits.
+func (idx *Indexer) Start() {
+	idx.rebuild()
+
+	go func() {
+		ticker := time.NewTicker(indexInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			idx.rebuild()
+		}
+	}()
+}
+
+// rebuild walks the base directory and atomically swaps in the new index, so
+// in-flight searches always see either the old or the new snapshot.
+func (idx *Indexer) rebuild() {
+	start := time.Now()
+	items := idx.walk()
+
+	idx.mu.Lock()
+	idx.items = items
+	idx.mu.Unlock()
+
+	log.Printf("Indexer: rebuilt index with %d items in %s", len(items), time.Since(start))
+}
+
+func (idx *Indexer) walk() []IndexFileItem {
+	var items []IndexFileItem
+
+	filepath.Walk(idx.baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		name := info.Name()
+
+		if config.IgnoreHidden && strings.HasPrefix(name, ".") && path != idx.baseDir {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			if name == "node_modules" || name == "build" || name == "dist" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(name))
+		if !isAllowedExt(ext) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(idx.baseDir, path)
+		if err != nil {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Indexer: failed to read %s: %v", path, err)
+			return nil
+		}
+
+		var title string
+		var tokens map[string]int
+		switch ext {
+		case ".md":
+			title, tokens = indexMarkdown(data)
+		case ".json":
+			title, tokens = indexJSON(data)
+		}
+
+		items = append(items, IndexFileItem{
+			Path:   filepath.ToSlash(relPath),
+			Info:   info,
+			Title:  title,
+			Tokens: tokens,
+		})
+		return nil
+	})
+
+	return items
+}
+
+func isAllowedExt(ext string) bool {
+	for _, allowed := range config.AllowedExts {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// indexMarkdown extracts the first H1 as the title and tokenizes the body
+// after stripping fenced code blocks.
+func indexMarkdown(data []byte) (string, map[string]int) {
+	var title string
+	var body strings.Builder
+	inFence := false
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if title == "" && strings.HasPrefix(trimmed, "# ") {
+			title = strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+			continue
+		}
+
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+
+	return title, tokenize(body.String())
+}
+
+// indexJSON recurses through the document collecting string leaves to
+// tokenize; the title falls back to a top-level "title" or "name" field.
+func indexJSON(data []byte) (string, map[string]int) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", nil
+	}
+
+	var title string
+	if m, ok := doc.(map[string]interface{}); ok {
+		for _, key := range []string{"title", "name"} {
+			if v, ok := m[key].(string); ok && title == "" {
+				title = v
+			}
+		}
+	}
+
+	var leaves []string
+	collectStringLeaves(doc, &leaves)
+
+	return title, tokenize(strings.Join(leaves, " "))
+}
+
+func collectStringLeaves(v interface{}, out *[]string) {
+	switch val := v.(type) {
+	case string:
+		*out = append(*out, val)
+	case map[string]interface{}:
+		for _, child := range val {
+			collectStringLeaves(child, out)
+		}
+	case []interface{}:
+		for _, child := range val {
+			collectStringLeaves(child, out)
+		}
+	}
+}
+
+func tokenize(s string) map[string]int {
+	tokens := make(map[string]int)
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		tokens[strings.ToLower(field)]++
+	}
+	return tokens
+}
+
+// searchHit is a ranked search result: the matched file plus a short snippet.
+// The snippet is the file's title, or for an untitled file the first query
+// token it contains; it does not include the surrounding text of the match.
+type searchHit struct {
+	FileInfo
+	Snippet string `json:"snippet"`
+	Score   int    `json:"score"`
+}
+
+// Search ranks the current index against the given query tokens, optionally
+// scoped to a subtree and/or extension.
+func (idx *Indexer) Search(query, dir, ext string, limit int) []searchHit {
+	queryTokens := strings.Fields(strings.ToLower(query))
+	if len(queryTokens) == 0 {
+		return nil
+	}
+
+	dir = filepath.ToSlash(filepath.Clean(dir))
+
+	idx.mu.RLock()
+	items := idx.items
+	idx.mu.RUnlock()
+
+	var hits []searchHit
+	for _, item := range items {
+		if dir != "" && dir != "." && !strings.HasPrefix(item.Path, dir+"/") && item.Path != dir {
+			continue
+		}
+		if ext != "" && strings.ToLower(filepath.Ext(item.Path)) != strings.ToLower(ext) {
+			continue
+		}
+
+		score := 0
+		for _, tok := range queryTokens {
+			score += item.Tokens[tok]
+			if strings.Contains(strings.ToLower(item.Title), tok) {
+				score += 5
+			}
+		}
+		if score == 0 {
+			continue
+		}
+
+		hits = append(hits, searchHit{
+			FileInfo: FileInfo{
+				Name:    filepath.Base(item.Path),
+				Path:    item.Path,
+				IsDir:   false,
+				Size:    item.Info.Size(),
+				ModTime: item.Info.ModTime(),
+			},
+			Snippet: snippetFor(item, queryTokens),
+			Score:   score,
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Path < hits[j].Path
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+func snippetFor(item IndexFileItem, queryTokens []string) string {
+	if item.Title != "" {
+		return item.Title
+	}
+	for _, tok := range queryTokens {
+		if item.Tokens[tok] > 0 {
+			return tok
+		}
+	}
+	return ""
+}
+
+// searchFiles handles GET /api/search?q=&dir=&ext=&limit=
+func searchFiles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "Missing q parameter", http.StatusBadRequest)
+		return
+	}
+
+	dir := r.URL.Query().Get("dir")
+	ext := r.URL.Query().Get("ext")
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	hits := indexer.Search(q, dir, ext, limit)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	jsonData, err := json.Marshal(hits)
+	if err != nil {
+		log.Printf("Error encoding search results: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(jsonData)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withTestConfig(t *testing.T) {
+	t.Helper()
+	prev := config
+	config = Config{AllowedExts: []string{".md", ".json"}, IgnoreHidden: true}
+	t.Cleanup(func() { config = prev })
+}
+
+func TestIndexerIncrementalRebuild(t *testing.T) {
+	withTestConfig(t)
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("# Title A\nhello world\n"), 0o644); err != nil {
+		t.Fatalf("writing a.md: %v", err)
+	}
+
+	idx := NewIndexer(dir)
+	idx.rebuild()
+
+	hits := idx.Search("hello", "", "", 10)
+	if len(hits) != 1 {
+		t.Fatalf("after first rebuild: got %d hits for %q, want 1", len(hits), "hello")
+	}
+
+	// Simulate the ticker firing again after a new file appears: the
+	// rebuild should pick it up without a restart.
+	if err := os.WriteFile(filepath.Join(dir, "b.md"), []byte("# Title B\nworld peace\n"), 0o644); err != nil {
+		t.Fatalf("writing b.md: %v", err)
+	}
+	idx.rebuild()
+
+	hits = idx.Search("world", "", "", 10)
+	if len(hits) != 2 {
+		t.Fatalf("after second rebuild: got %d hits for %q, want 2", len(hits), "world")
+	}
+
+	// And a file removed between rebuilds should disappear from the index.
+	if err := os.Remove(filepath.Join(dir, "a.md")); err != nil {
+		t.Fatalf("removing a.md: %v", err)
+	}
+	idx.rebuild()
+
+	hits = idx.Search("hello", "", "", 10)
+	if len(hits) != 0 {
+		t.Fatalf("after third rebuild: got %d hits for removed file, want 0", len(hits))
+	}
+}
+
+func TestIndexerDirScoping(t *testing.T) {
+	withTestConfig(t)
+	dir := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("creating sub: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "top.md"), []byte("# Top\nfindme token\n"), 0o644); err != nil {
+		t.Fatalf("writing top.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "nested.md"), []byte("# Nested\nfindme token\n"), 0o644); err != nil {
+		t.Fatalf("writing nested.md: %v", err)
+	}
+
+	idx := NewIndexer(dir)
+	idx.rebuild()
+
+	all := idx.Search("findme", "", "", 10)
+	if len(all) != 2 {
+		t.Fatalf("unscoped search: got %d hits, want 2", len(all))
+	}
+
+	scoped := idx.Search("findme", "sub", "", 10)
+	if len(scoped) != 1 {
+		t.Fatalf("dir=sub search: got %d hits, want 1", len(scoped))
+	}
+	if scoped[0].Path != "sub/nested.md" {
+		t.Fatalf("dir=sub search matched %q, want %q", scoped[0].Path, "sub/nested.md")
+	}
+
+	none := idx.Search("findme", "missing", "", 10)
+	if len(none) != 0 {
+		t.Fatalf("dir=missing search: got %d hits, want 0", len(none))
+	}
+}
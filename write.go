@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxUploadBytes caps the size of an uploaded request body: http.MaxBytesReader
+// enforces it as a hard limit, and ParseMultipartForm uses it as the amount
+// of the form it may hold in memory.
+const maxUploadBytes = 32 << 20 // 32 MiB
+
+// requireWritable 403s and returns false when targetDir (or any
+// .fileserver.yml between baseDir and targetDir) is read-only, the single
+// gate every write endpoint must pass through before touching the
+// filesystem.
+func requireWritable(w http.ResponseWriter, baseDir, targetDir string) bool {
+	if *EffectiveConfig(baseDir, targetDir).ReadOnly {
+		http.Error(w, "Server is running in read-only mode", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// contentHandler dispatches "/api/content/" by method: GET keeps the
+// existing read behavior, POST uploads (creating or overwriting) a file,
+// and DELETE removes one.
+func contentHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		getFileContent(w, r)
+	case http.MethodPost:
+		uploadFile(w, r)
+	case http.MethodDelete:
+		deleteFile(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// resolveForWrite resolves the directory portion of relPath with
+// SafeResolve (so it must already exist and stay rooted in baseDir), then
+// joins the final path segment, rejecting anything but a plain filename.
+func resolveForWrite(baseDir, relPath string) (string, error) {
+	dir, name := filepath.Split(relPath)
+	if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+		return "", ErrOutsideRoot
+	}
+
+	dirAbs, err := SafeResolve(baseDir, dir)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dirAbs, name), nil
+}
+
+// uploadFile handles POST /api/content/<name>?dir=<dir>, a multipart
+// upload under the "file" field. The same extension allowlist as reads
+// applies and the body is capped at 32 MiB; the upload is streamed to a temp file in the
+// destination directory and then os.Rename'd into place so a reader never
+// observes a partially written file.
+func uploadFile(w http.ResponseWriter, r *http.Request) {
+	requestedFile := path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/api/content/"))
+	requestedDir := r.URL.Query().Get("dir")
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	destPath, err := resolveForWrite(baseDir, filepath.Join(requestedDir, strings.TrimPrefix(requestedFile, "/")))
+	if err != nil {
+		log.Printf("upload: error resolving destination: %v", err)
+		if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Destination directory not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	// Gate on the directory the file actually lands in, not just the dir=
+	// query param — requestedFile can itself carry subdirectory segments
+	// (e.g. "sub/x.md") that a .fileserver.yml down there needs to govern.
+	if !requireWritable(w, baseDir, filepath.Dir(destPath)) {
+		return
+	}
+
+	effective := EffectiveConfig(baseDir, filepath.Dir(destPath))
+	ext := strings.ToLower(filepath.Ext(requestedFile))
+	allowed := false
+	for _, allowedExt := range effective.AllowedExts {
+		if ext == allowedExt {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		http.Error(w, fmt.Sprintf("Unsupported file type: %s", ext), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	if err := r.ParseMultipartForm(maxUploadBytes); err != nil {
+		http.Error(w, "Invalid upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	src, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing \"file\" form field", http.StatusBadRequest)
+		return
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".upload-*")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	written, err := io.Copy(tmp, src)
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("action=upload user=- path=%s bytes=%d", destPath, written)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(FileInfo{
+		Name:  filepath.Base(destPath),
+		Path:  requestedFile,
+		IsDir: false,
+		Size:  written,
+	})
+}
+
+// deleteFile handles DELETE /api/content/<name>?dir=<dir>.
+func deleteFile(w http.ResponseWriter, r *http.Request) {
+	requestedFile := path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/api/content/"))
+	requestedDir := r.URL.Query().Get("dir")
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filePath, err := SafeResolve(baseDir, filepath.Join(requestedDir, strings.TrimPrefix(requestedFile, "/")))
+	if err != nil {
+		log.Printf("delete: error resolving %s: %v", requestedFile, err)
+		if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
+		}
+		return
+	}
+
+	// Gate on the resolved file's own directory, not just the dir= query
+	// param — requestedFile can carry subdirectory segments of its own.
+	if !requireWritable(w, baseDir, filepath.Dir(filePath)) {
+		return
+	}
+
+	if _, err := requireRegularFile(filePath); err != nil {
+		if errors.Is(err, ErrNotRegular) {
+			http.Error(w, "Cannot delete a directory", http.StatusBadRequest)
+		} else {
+			http.Error(w, fmt.Sprintf("File not found: %s", requestedFile), http.StatusNotFound)
+		}
+		return
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("action=delete user=- path=%s", filePath)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// moveRequest is the JSON body accepted by POST /api/move.
+type moveRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Dir  string `json:"dir"`
+}
+
+// moveFile handles POST /api/move, renaming a file within the root. Both
+// "from" and "to" are resolved relative to "dir" (defaulting to the root).
+func moveFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req moveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.From == "" || req.To == "" {
+		http.Error(w, "Missing from or to", http.StatusBadRequest)
+		return
+	}
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fromPath, err := SafeResolve(baseDir, filepath.Join(req.Dir, req.From))
+	if err != nil {
+		log.Printf("move: error resolving source %s: %v", req.From, err)
+		if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, fmt.Sprintf("File not found: %s", req.From), http.StatusNotFound)
+		}
+		return
+	}
+	if _, err := requireRegularFile(fromPath); err != nil {
+		http.Error(w, "Source must be an existing file", http.StatusBadRequest)
+		return
+	}
+
+	// Gate on the resolved source directory — req.Dir alone misses a
+	// .fileserver.yml that lives deeper, under a subdirectory carried in
+	// req.From.
+	if !requireWritable(w, baseDir, filepath.Dir(fromPath)) {
+		return
+	}
+
+	toPath, err := resolveForWrite(baseDir, filepath.Join(req.Dir, req.To))
+	if err != nil {
+		log.Printf("move: error resolving destination %s: %v", req.To, err)
+		if errors.Is(err, ErrOutsideRoot) {
+			http.Error(w, "Invalid file path", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Destination directory not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	// Same for the resolved destination directory, and check the
+	// extension allowlist against it rather than the shallower req.Dir.
+	if !requireWritable(w, baseDir, filepath.Dir(toPath)) {
+		return
+	}
+
+	effective := EffectiveConfig(baseDir, filepath.Dir(toPath))
+	destExt := strings.ToLower(filepath.Ext(req.To))
+	allowed := false
+	for _, allowedExt := range effective.AllowedExts {
+		if destExt == allowedExt {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		http.Error(w, fmt.Sprintf("Unsupported file type: %s", destExt), http.StatusBadRequest)
+		return
+	}
+
+	if err := os.Rename(fromPath, toPath); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("action=move user=- from=%s to=%s", fromPath, toPath)
+	w.WriteHeader(http.StatusNoContent)
+}